@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/jhoelzel/go-wait-for-k8s/pkg/waiter"
+)
+
+func main() {
+	// command-line flags and their default values
+	var (
+		namespace          = flag.String("namespace", "", "The namespace to monitor")
+		labelSelector      = flag.String("label-selector", "", "The label selector to filter resources")
+		resourceType       = flag.String("resource-type", "", "The resource type to monitor: 'pod', 'job', 'deployment', 'statefulset', 'daemonset', 'replicaset', 'service', 'pvc', 'namespace', 'crd', or 'cronjob'")
+		gvr                = flag.String("gvr", "", "GroupVersionResource to wait on generically, e.g. 'cert-manager.io/v1/certificates'. Overrides --resource-type; requires --jsonpath")
+		jsonPathExpr       = flag.String("jsonpath", "", "JSONPath template evaluated against each matching object, e.g. '{.status.conditions[?(@.type==\"Ready\")].status}'. Required with --gvr")
+		jsonPathValue      = flag.String("jsonpath-value", "True", "Value the --jsonpath result must equal for an object to be considered ready")
+		waitSpecPath       = flag.String("wait-spec", "", "Path to a YAML file listing multiple heterogeneous wait targets to wait on simultaneously. Overrides --resource-type and --gvr")
+		output             = flag.String("output", "text", "Progress output format: 'text' (human-readable), 'json' (single result object at the end), or 'events' (one JSON object per readiness transition)")
+		failFast           = flag.Bool("fail-fast", false, "Abort immediately with a non-zero exit code if a matching resource reaches a terminal failure state (e.g. a Job past its backoff limit, a Pod in ImagePullBackOff/CrashLoopBackOff, a Deployment with ProgressDeadlineExceeded) instead of waiting until --timeout")
+		kubeconfigPath     = flag.String("kubeconfig", "", "Path to the kubeconfig file")
+		timeout            = flag.Int("timeout", 0, "The maximum amount of time to wait for resources to become ready, default is infinite")
+		validResourceTypes = map[string]bool{
+			"pod":         true,
+			"job":         true,
+			"deployment":  true,
+			"statefulset": true,
+			"daemonset":   true,
+			"replicaset":  true,
+			"service":     true,
+			"pvc":         true,
+			"namespace":   true,
+			"crd":         true,
+			"cronjob":     true,
+		}
+	)
+	flag.Parse()
+
+	// Update values of flags from environment variables if they are not provided as command-line arguments
+	if *namespace == "" {
+		if ns := os.Getenv("NAMESPACE"); ns != "" {
+			*namespace = ns
+		}
+	}
+
+	if *labelSelector == "" {
+		if ls := os.Getenv("LABEL_SELECTOR"); ls != "" {
+			*labelSelector = ls
+		}
+	}
+
+	if *resourceType == "" {
+		if rt := os.Getenv("RESOURCE_TYPE"); rt != "" {
+			*resourceType = rt
+		}
+	}
+
+	if *kubeconfigPath == "" {
+		if kc := os.Getenv("KUBECONFIG"); kc != "" {
+			*kubeconfigPath = kc
+		}
+	}
+	if *timeout == 0 {
+		if tOut := os.Getenv("TIMEOUT_SECONDS"); tOut != "" {
+			i, err := strconv.Atoi(tOut)
+			if err != nil {
+				log.Fatalf("expected an integer value for timeout but got: %s, with error: %v", tOut, err)
+			}
+			*timeout = i
+		}
+	}
+	// --wait-spec waits on a whole manifest of heterogeneous targets and
+	// bypasses --resource-type/--gvr entirely; --gvr takes a user-supplied
+	// GroupVersionResource and JSONPath predicate instead of one of the
+	// built-in resource types, so it can wait on any single CRD.
+	if *waitSpecPath == "" {
+		if *gvr != "" {
+			if *jsonPathExpr == "" {
+				log.Fatalf("--jsonpath is required when --gvr is set")
+			}
+			if *failFast {
+				log.Fatalf("--fail-fast is not supported with --gvr: there's no general notion of a terminal failure for an arbitrary CRD")
+			}
+		} else if !validResourceTypes[*resourceType] {
+			log.Fatalf("Invalid resource type: %s. Supported resource types are: 'pod', 'job', 'deployment', 'statefulset', 'daemonset', 'replicaset', 'service', 'pvc', 'namespace', 'crd', and 'cronjob'.", *resourceType)
+		}
+	}
+
+	reporter, err := waiter.NewReporter(*output)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	//kubeconfig := os.Getenv("KUBECONFIG")
+	*kubeconfigPath = "/home/vscode/.kube/config"
+	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfigPath)
+	if err != nil {
+		config, err = rest.InClusterConfig()
+		if err != nil {
+			log.Fatalf("Failed to load Kubernetes config: %v", err)
+		}
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		log.Fatalf("Failed to create Kubernetes client: %v", err)
+	}
+	apiextClientset, err := apiextensionsclientset.NewForConfig(config)
+	if err != nil {
+		log.Fatalf("Failed to create apiextensions client: %v", err)
+	}
+	clients := &waiter.Clients{Kube: clientset, APIExt: apiextClientset}
+
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		log.Fatalf("Failed to create dynamic client: %v", err)
+	}
+	w := waiter.NewWaiter(clients, dynamicClient, reporter)
+
+	ctx := context.Background()
+	// Create a new context with the provided timeout if provided
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(*timeout)*time.Minute)
+		defer cancel()
+	}
+
+	if *waitSpecPath != "" {
+		spec, err := waiter.LoadWaitSpec(*waitSpecPath)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		if *failFast {
+			// --fail-fast on the CLI applies to every target; a target can
+			// also opt in on its own via the spec file's failFast field.
+			// GVR targets are skipped: there's no general notion of a
+			// terminal failure for an arbitrary CRD, the same reason --gvr
+			// rejects --fail-fast outside of --wait-spec.
+			for i := range spec.Targets {
+				if spec.Targets[i].GVR != "" {
+					continue
+				}
+				spec.Targets[i].FailFast = true
+			}
+		}
+		err = waiter.RunWaitSpec(ctx, w, spec)
+		reporter.Summary(err == nil, err)
+		if err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *gvr != "" {
+		err := w.WaitFor(ctx, waiter.Target{GVR: *gvr, Namespace: *namespace, LabelSelector: *labelSelector, JSONPath: *jsonPathExpr, JSONPathValue: *jsonPathValue})
+		reporter.Summary(err == nil, err)
+		if err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Block until every matching resource reports ready, reconnecting the
+	// underlying watch as needed, or until the context above times out.
+	err = w.WaitFor(ctx, waiter.Target{Kind: *resourceType, Namespace: *namespace, LabelSelector: *labelSelector, FailFast: *failFast})
+	reporter.Summary(err == nil, err)
+	if err != nil {
+		os.Exit(1)
+	}
+}
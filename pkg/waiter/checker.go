@@ -0,0 +1,382 @@
+package waiter
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Clients bundles the API clients a Checker may need beyond the object it was
+// handed, e.g. to look up a Service's Endpoints, and that the wait engine uses
+// to list and watch objects. Kube is required; APIExt is only needed to list
+// and watch the "crd" resource type.
+type Clients struct {
+	Kube   kubernetes.Interface
+	APIExt apiextensionsclientset.Interface
+}
+
+// Checker decides whether a single object of a given resource type has
+// reached a ready state. Implementations are registered per resource type via
+// RegisterChecker so that callers (and users embedding this tool) can replace
+// the readiness logic of a built-in resource type. Objects are only listed and
+// watched for the built-in resource types, so a Checker registered under any
+// other name is never consulted; CRDs are waited on through a GVR Target.
+// The returned reason explains why an object isn't ready yet (e.g.
+// "ImagePullBackOff"); it's surfaced by the progress reporter and is empty
+// once ready or when there's nothing more specific to say.
+type Checker interface {
+	IsReady(ctx context.Context, clients *Clients, obj runtime.Object) (ready bool, reason string, err error)
+}
+
+// CheckerFunc adapts a plain function to the Checker interface.
+type CheckerFunc func(ctx context.Context, clients *Clients, obj runtime.Object) (bool, string, error)
+
+func (f CheckerFunc) IsReady(ctx context.Context, clients *Clients, obj runtime.Object) (bool, string, error) {
+	return f(ctx, clients, obj)
+}
+
+var checkers = map[string]Checker{}
+
+// RegisterChecker associates resourceType with c, overriding any previously
+// registered checker for that type. Only the built-in resource types ("pod",
+// "deployment", "crd", ...) are listed and watched, so registering a checker
+// under a new name does not make that kind waitable.
+func RegisterChecker(resourceType string, c Checker) {
+	checkers[resourceType] = c
+}
+
+func init() {
+	RegisterChecker("pod", CheckerFunc(checkPodReady))
+	RegisterChecker("job", CheckerFunc(checkJobReady))
+	RegisterChecker("deployment", CheckerFunc(checkDeploymentReady))
+	RegisterChecker("statefulset", CheckerFunc(checkStatefulSetReady))
+	RegisterChecker("daemonset", CheckerFunc(checkDaemonSetReady))
+	RegisterChecker("replicaset", CheckerFunc(checkReplicaSetReady))
+	RegisterChecker("service", CheckerFunc(checkServiceReady))
+	RegisterChecker("pvc", CheckerFunc(checkPVCReady))
+	RegisterChecker("namespace", CheckerFunc(checkNamespaceReady))
+	RegisterChecker("crd", CheckerFunc(checkCRDReady))
+	RegisterChecker("cronjob", CheckerFunc(checkCronJobReady))
+}
+
+// isResourceReady looks up the Checker registered for resourceType and runs
+// it against obj.
+func isResourceReady(ctx context.Context, clients *Clients, resourceType string, obj runtime.Object) (bool, string, error) {
+	checker, ok := checkers[resourceType]
+	if !ok {
+		return false, "", fmt.Errorf("no readiness checker registered for resource type: %s", resourceType)
+	}
+	return checker.IsReady(ctx, clients, obj)
+}
+
+// TerminalChecker recognizes when an object of a given resource type has
+// reached a state it cannot recover from on its own, e.g. a Job that has
+// exhausted its backoff limit or a Pod stuck in ImagePullBackOff. It's
+// consulted only when the caller opted into --fail-fast, since under normal
+// operation these states are just points along the way to readiness (a
+// CrashLoopBackOff pod might still be restarted successfully by the user).
+type TerminalChecker interface {
+	IsTerminallyFailed(ctx context.Context, clients *Clients, obj runtime.Object) (failed bool, reason string, err error)
+}
+
+// TerminalCheckerFunc adapts a plain function to the TerminalChecker interface.
+type TerminalCheckerFunc func(ctx context.Context, clients *Clients, obj runtime.Object) (bool, string, error)
+
+func (f TerminalCheckerFunc) IsTerminallyFailed(ctx context.Context, clients *Clients, obj runtime.Object) (bool, string, error) {
+	return f(ctx, clients, obj)
+}
+
+var terminalCheckers = map[string]TerminalChecker{}
+
+// RegisterTerminalChecker associates resourceType with c, so --fail-fast can
+// recognize a doomed object of that type. Resource types with no registered
+// TerminalChecker are simply never considered terminally failed.
+func RegisterTerminalChecker(resourceType string, c TerminalChecker) {
+	terminalCheckers[resourceType] = c
+}
+
+func init() {
+	RegisterTerminalChecker("pod", TerminalCheckerFunc(checkPodTerminallyFailed))
+	RegisterTerminalChecker("job", TerminalCheckerFunc(checkJobTerminallyFailed))
+	RegisterTerminalChecker("deployment", TerminalCheckerFunc(checkDeploymentTerminallyFailed))
+}
+
+// isResourceTerminallyFailed reports whether obj is doomed, using whatever
+// TerminalChecker is registered for resourceType. A resource type with no
+// TerminalChecker registered is reported as never terminally failed.
+func isResourceTerminallyFailed(ctx context.Context, clients *Clients, resourceType string, obj runtime.Object) (bool, string, error) {
+	checker, ok := terminalCheckers[resourceType]
+	if !ok {
+		return false, "", nil
+	}
+	return checker.IsTerminallyFailed(ctx, clients, obj)
+}
+
+// podBackOffReasons are the container waiting reasons kubectl treats as
+// "this pod is not going to start on its own": CrashLoopBackOff,
+// ImagePullBackOff and ErrImagePull. Only these exact reasons are matched;
+// other waiting reasons, including other *BackOff ones, are not.
+var podBackOffReasons = map[string]bool{
+	"CrashLoopBackOff": true,
+	"ImagePullBackOff": true,
+	"ErrImagePull":     true,
+}
+
+func checkPodTerminallyFailed(ctx context.Context, clients *Clients, obj runtime.Object) (bool, string, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return false, "", fmt.Errorf("expected *corev1.Pod, got %T", obj)
+	}
+	if pod.Status.Phase == corev1.PodFailed {
+		return true, fmt.Sprintf("pod failed: %s", pod.Status.Reason), nil
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && podBackOffReasons[cs.State.Waiting.Reason] {
+			return true, fmt.Sprintf("container %s: %s", cs.Name, cs.State.Waiting.Reason), nil
+		}
+	}
+	return false, "", nil
+}
+
+func checkJobTerminallyFailed(ctx context.Context, clients *Clients, obj runtime.Object) (bool, string, error) {
+	job, ok := obj.(*batchv1.Job)
+	if !ok {
+		return false, "", fmt.Errorf("expected *batchv1.Job, got %T", obj)
+	}
+	backoffLimit := int32(6) // kubectl/kube-controller-manager default when unset
+	if job.Spec.BackoffLimit != nil {
+		backoffLimit = *job.Spec.BackoffLimit
+	}
+	if job.Status.Failed > backoffLimit {
+		return true, fmt.Sprintf("%d failed pod(s) exceeds backoff limit %d", job.Status.Failed, backoffLimit), nil
+	}
+	return false, "", nil
+}
+
+func checkDeploymentTerminallyFailed(ctx context.Context, clients *Clients, obj runtime.Object) (bool, string, error) {
+	deployment, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return false, "", fmt.Errorf("expected *appsv1.Deployment, got %T", obj)
+	}
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing &&
+			condition.Status == corev1.ConditionFalse &&
+			condition.Reason == "ProgressDeadlineExceeded" {
+			return true, fmt.Sprintf("%s: %s", condition.Reason, condition.Message), nil
+		}
+	}
+	return false, "", nil
+}
+
+func checkPodReady(ctx context.Context, clients *Clients, obj runtime.Object) (bool, string, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return false, "", fmt.Errorf("expected *corev1.Pod, got %T", obj)
+	}
+	// A Pod can report the PodReady condition True before its containers have
+	// actually started (e.g. readiness gates), so check container state
+	// directly instead of trusting the condition alone.
+	if pod.Status.Phase == corev1.PodSucceeded {
+		return true, "", nil
+	}
+	if pod.Status.Phase == corev1.PodFailed {
+		return false, fmt.Sprintf("pod failed: %s", pod.Status.Reason), nil
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return false, fmt.Sprintf("pod is %s", pod.Status.Phase), nil
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting != nil {
+			return false, fmt.Sprintf("container %s: %s", cs.Name, cs.State.Waiting.Reason), nil
+		}
+		if cs.State.Running == nil && cs.State.Terminated == nil {
+			return false, fmt.Sprintf("container %s not started", cs.Name), nil
+		}
+	}
+	return true, "", nil
+}
+
+func checkJobReady(ctx context.Context, clients *Clients, obj runtime.Object) (bool, string, error) {
+	job, ok := obj.(*batchv1.Job)
+	if !ok {
+		return false, "", fmt.Errorf("expected *batchv1.Job, got %T", obj)
+	}
+	if job.Status.Succeeded > 0 {
+		return true, "", nil
+	}
+	if job.Status.Failed > 0 {
+		return false, fmt.Sprintf("%d pod(s) failed", job.Status.Failed), nil
+	}
+	return false, "", nil
+}
+
+func checkDeploymentReady(ctx context.Context, clients *Clients, obj runtime.Object) (bool, string, error) {
+	deployment, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return false, "", fmt.Errorf("expected *appsv1.Deployment, got %T", obj)
+	}
+	// A Deployment can satisfy the replica counts below from a *previous*
+	// rollout while the newest ReplicaSet never got scheduled; ObservedGeneration
+	// lagging Generation means the controller hasn't even looked at the latest
+	// spec yet, and ProgressDeadlineExceeded means it looked and gave up.
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return false, "waiting for controller to observe latest revision", nil
+	}
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Status == corev1.ConditionFalse {
+			return false, fmt.Sprintf("%s: %s", condition.Reason, condition.Message), nil
+		}
+	}
+	if deployment.Status.UpdatedReplicas == *deployment.Spec.Replicas &&
+		deployment.Status.AvailableReplicas == *deployment.Spec.Replicas {
+		return true, "", nil
+	}
+	return false, fmt.Sprintf("%d/%d replicas available", deployment.Status.AvailableReplicas, *deployment.Spec.Replicas), nil
+}
+
+func checkStatefulSetReady(ctx context.Context, clients *Clients, obj runtime.Object) (bool, string, error) {
+	statefulSet, ok := obj.(*appsv1.StatefulSet)
+	if !ok {
+		return false, "", fmt.Errorf("expected *appsv1.StatefulSet, got %T", obj)
+	}
+	if statefulSet.Status.ReadyReplicas == *statefulSet.Spec.Replicas {
+		return true, "", nil
+	}
+	return false, fmt.Sprintf("%d/%d replicas ready", statefulSet.Status.ReadyReplicas, *statefulSet.Spec.Replicas), nil
+}
+
+func checkDaemonSetReady(ctx context.Context, clients *Clients, obj runtime.Object) (bool, string, error) {
+	daemonSet, ok := obj.(*appsv1.DaemonSet)
+	if !ok {
+		return false, "", fmt.Errorf("expected *appsv1.DaemonSet, got %T", obj)
+	}
+	if daemonSet.Status.DesiredNumberScheduled == daemonSet.Status.NumberReady {
+		return true, "", nil
+	}
+	return false, fmt.Sprintf("%d/%d ready", daemonSet.Status.NumberReady, daemonSet.Status.DesiredNumberScheduled), nil
+}
+
+func checkReplicaSetReady(ctx context.Context, clients *Clients, obj runtime.Object) (bool, string, error) {
+	replicaSet, ok := obj.(*appsv1.ReplicaSet)
+	if !ok {
+		return false, "", fmt.Errorf("expected *appsv1.ReplicaSet, got %T", obj)
+	}
+	if replicaSet.Status.ReadyReplicas == *replicaSet.Spec.Replicas {
+		return true, "", nil
+	}
+	return false, fmt.Sprintf("%d/%d replicas ready", replicaSet.Status.ReadyReplicas, *replicaSet.Spec.Replicas), nil
+}
+
+// checkServiceReady considers a LoadBalancer Service ready once it has been
+// assigned an ingress address, an ExternalName Service ready right away, and
+// any other Service ready once it has at least one endpoint address backing it.
+func checkServiceReady(ctx context.Context, clients *Clients, obj runtime.Object) (bool, string, error) {
+	svc, ok := obj.(*corev1.Service)
+	if !ok {
+		return false, "", fmt.Errorf("expected *corev1.Service, got %T", obj)
+	}
+	if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		if len(svc.Status.LoadBalancer.Ingress) > 0 {
+			return true, "", nil
+		}
+		return false, "waiting for load balancer ingress", nil
+	}
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		return true, "", nil
+	}
+	if clients == nil || clients.Kube == nil {
+		return false, "", fmt.Errorf("service readiness requires a Kubernetes client to inspect endpoints")
+	}
+	endpoints, err := clients.Kube.CoreV1().Endpoints(svc.Namespace).Get(ctx, svc.Name, metav1.GetOptions{})
+	if err != nil {
+		// A Service's Endpoints object is created by the endpoints
+		// controller once it has something to say about the selector, or
+		// never at all for a selectorless Service the user manages by hand;
+		// either way NotFound just means "nothing to report yet", not a
+		// hard failure.
+		if apierrors.IsNotFound(err) {
+			return false, "no endpoints object yet", nil
+		}
+		return false, "", fmt.Errorf("failed to get endpoints for service %s: %v", svc.Name, err)
+	}
+	for _, subset := range endpoints.Subsets {
+		if len(subset.Addresses) > 0 {
+			return true, "", nil
+		}
+	}
+	return false, "no endpoint addresses", nil
+}
+
+func checkPVCReady(ctx context.Context, clients *Clients, obj runtime.Object) (bool, string, error) {
+	pvc, ok := obj.(*corev1.PersistentVolumeClaim)
+	if !ok {
+		return false, "", fmt.Errorf("expected *corev1.PersistentVolumeClaim, got %T", obj)
+	}
+	if pvc.Status.Phase == corev1.ClaimBound {
+		return true, "", nil
+	}
+	return false, fmt.Sprintf("claim is %s", pvc.Status.Phase), nil
+}
+
+func checkNamespaceReady(ctx context.Context, clients *Clients, obj runtime.Object) (bool, string, error) {
+	ns, ok := obj.(*corev1.Namespace)
+	if !ok {
+		return false, "", fmt.Errorf("expected *corev1.Namespace, got %T", obj)
+	}
+	if ns.Status.Phase == corev1.NamespaceActive {
+		return true, "", nil
+	}
+	return false, fmt.Sprintf("namespace is %s", ns.Status.Phase), nil
+}
+
+// checkCRDReady mirrors Helm's CRD readiness check: the CRD must have been
+// accepted (names are free of conflicts) and established (its REST endpoints
+// are being served) before instances of it can reliably be created.
+func checkCRDReady(ctx context.Context, clients *Clients, obj runtime.Object) (bool, string, error) {
+	crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition)
+	if !ok {
+		return false, "", fmt.Errorf("expected *apiextensionsv1.CustomResourceDefinition, got %T", obj)
+	}
+	var established, namesAccepted bool
+	var reason string
+	for _, condition := range crd.Status.Conditions {
+		switch condition.Type {
+		case apiextensionsv1.Established:
+			established = condition.Status == apiextensionsv1.ConditionTrue
+		case apiextensionsv1.NamesAccepted:
+			namesAccepted = condition.Status == apiextensionsv1.ConditionTrue
+			if !namesAccepted {
+				reason = condition.Reason
+			}
+		}
+	}
+	if established && namesAccepted {
+		return true, "", nil
+	}
+	if reason == "" {
+		reason = "waiting for CRD to be established"
+	}
+	return false, reason, nil
+}
+
+// checkCronJobReady has no rollout to converge on, so a CronJob is considered
+// ready as soon as it exists and isn't suspended.
+func checkCronJobReady(ctx context.Context, clients *Clients, obj runtime.Object) (bool, string, error) {
+	cronJob, ok := obj.(*batchv1.CronJob)
+	if !ok {
+		return false, "", fmt.Errorf("expected *batchv1.CronJob, got %T", obj)
+	}
+	if cronJob.Spec.Suspend == nil || !*cronJob.Spec.Suspend {
+		return true, "", nil
+	}
+	return false, "CronJob is suspended", nil
+}
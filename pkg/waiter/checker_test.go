@@ -0,0 +1,328 @@
+package waiter
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func int32ptr(i int32) *int32 { return &i }
+
+func TestCheckServiceReady(t *testing.T) {
+	t.Run("load balancer without ingress", func(t *testing.T) {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+		}
+		ready, reason, err := checkServiceReady(context.Background(), &Clients{Kube: fake.NewSimpleClientset()}, svc)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ready || reason == "" {
+			t.Errorf("ready = %v, reason = %q, want not ready with a reason", ready, reason)
+		}
+	})
+
+	t.Run("load balancer with ingress", func(t *testing.T) {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+			Status: corev1.ServiceStatus{
+				LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "1.2.3.4"}}},
+			},
+		}
+		ready, _, err := checkServiceReady(context.Background(), &Clients{Kube: fake.NewSimpleClientset()}, svc)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !ready {
+			t.Errorf("ready = false, want true")
+		}
+	})
+
+	t.Run("external name is trivially ready", func(t *testing.T) {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeExternalName},
+		}
+		ready, _, err := checkServiceReady(context.Background(), &Clients{Kube: fake.NewSimpleClientset()}, svc)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !ready {
+			t.Errorf("ready = false, want true")
+		}
+	})
+
+	t.Run("no endpoints object yet is not-ready, not an error", func(t *testing.T) {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+		}
+		ready, reason, err := checkServiceReady(context.Background(), &Clients{Kube: fake.NewSimpleClientset()}, svc)
+		if err != nil {
+			t.Fatalf("expected no error for a missing Endpoints object, got: %v", err)
+		}
+		if ready {
+			t.Errorf("ready = true, want false")
+		}
+		if reason != "no endpoints object yet" {
+			t.Errorf("reason = %q, want %q", reason, "no endpoints object yet")
+		}
+	})
+
+	t.Run("endpoints with no addresses", func(t *testing.T) {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+		}
+		endpoints := &corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default"}}
+		ready, reason, err := checkServiceReady(context.Background(), &Clients{Kube: fake.NewSimpleClientset(endpoints)}, svc)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ready || reason != "no endpoint addresses" {
+			t.Errorf("ready = %v, reason = %q, want not ready with %q", ready, reason, "no endpoint addresses")
+		}
+	})
+
+	t.Run("endpoints with addresses", func(t *testing.T) {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+		}
+		endpoints := &corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default"},
+			Subsets:    []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}}},
+		}
+		ready, _, err := checkServiceReady(context.Background(), &Clients{Kube: fake.NewSimpleClientset(endpoints)}, svc)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !ready {
+			t.Errorf("ready = false, want true")
+		}
+	})
+}
+
+func TestCheckDeploymentReady(t *testing.T) {
+	cases := []struct {
+		name       string
+		deployment *appsv1.Deployment
+		wantReady  bool
+	}{
+		{
+			name: "observed generation lags",
+			deployment: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       appsv1.DeploymentSpec{Replicas: int32ptr(1)},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 1},
+			},
+			wantReady: false,
+		},
+		{
+			name: "progress deadline exceeded",
+			deployment: &appsv1.Deployment{
+				Spec: appsv1.DeploymentSpec{Replicas: int32ptr(1)},
+				Status: appsv1.DeploymentStatus{
+					Conditions: []appsv1.DeploymentCondition{
+						{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"},
+					},
+				},
+			},
+			wantReady: false,
+		},
+		{
+			name: "replicas not yet available",
+			deployment: &appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: int32ptr(3)},
+				Status: appsv1.DeploymentStatus{UpdatedReplicas: 3, AvailableReplicas: 2},
+			},
+			wantReady: false,
+		},
+		{
+			name: "fully rolled out",
+			deployment: &appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: int32ptr(3)},
+				Status: appsv1.DeploymentStatus{UpdatedReplicas: 3, AvailableReplicas: 3},
+			},
+			wantReady: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ready, _, err := checkDeploymentReady(context.Background(), nil, tc.deployment)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ready != tc.wantReady {
+				t.Errorf("ready = %v, want %v", ready, tc.wantReady)
+			}
+		})
+	}
+}
+
+func TestCheckCRDReady(t *testing.T) {
+	cases := []struct {
+		name       string
+		conditions []apiextensionsv1.CustomResourceDefinitionCondition
+		wantReady  bool
+		wantReason string
+	}{
+		{
+			name: "established and accepted",
+			conditions: []apiextensionsv1.CustomResourceDefinitionCondition{
+				{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue},
+				{Type: apiextensionsv1.NamesAccepted, Status: apiextensionsv1.ConditionTrue},
+			},
+			wantReady: true,
+		},
+		{
+			name: "names not accepted surfaces the condition's reason",
+			conditions: []apiextensionsv1.CustomResourceDefinitionCondition{
+				{Type: apiextensionsv1.NamesAccepted, Status: apiextensionsv1.ConditionFalse, Reason: "NameConflict"},
+			},
+			wantReady:  false,
+			wantReason: "NameConflict",
+		},
+		{
+			name:       "no conditions yet",
+			conditions: nil,
+			wantReady:  false,
+			wantReason: "waiting for CRD to be established",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			crd := &apiextensionsv1.CustomResourceDefinition{Status: apiextensionsv1.CustomResourceDefinitionStatus{Conditions: tc.conditions}}
+			ready, reason, err := checkCRDReady(context.Background(), nil, crd)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ready != tc.wantReady {
+				t.Errorf("ready = %v, want %v", ready, tc.wantReady)
+			}
+			if reason != tc.wantReason {
+				t.Errorf("reason = %q, want %q", reason, tc.wantReason)
+			}
+		})
+	}
+}
+
+func TestCheckPodTerminallyFailed(t *testing.T) {
+	cases := []struct {
+		name       string
+		pod        *corev1.Pod
+		wantFailed bool
+	}{
+		{
+			name:       "running",
+			pod:        &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+			wantFailed: false,
+		},
+		{
+			name:       "failed phase",
+			pod:        &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted"}},
+			wantFailed: true,
+		},
+		{
+			name: "crash loop backoff",
+			pod: &corev1.Pod{Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "app", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+				},
+			}},
+			wantFailed: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			failed, _, err := checkPodTerminallyFailed(context.Background(), nil, tc.pod)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if failed != tc.wantFailed {
+				t.Errorf("failed = %v, want %v", failed, tc.wantFailed)
+			}
+		})
+	}
+}
+
+func TestCheckJobTerminallyFailed(t *testing.T) {
+	cases := []struct {
+		name       string
+		job        *batchv1.Job
+		wantFailed bool
+	}{
+		{
+			name:       "within default backoff limit",
+			job:        &batchv1.Job{Status: batchv1.JobStatus{Failed: 3}},
+			wantFailed: false,
+		},
+		{
+			name:       "exceeds default backoff limit",
+			job:        &batchv1.Job{Status: batchv1.JobStatus{Failed: 7}},
+			wantFailed: true,
+		},
+		{
+			name: "exceeds explicit backoff limit",
+			job: &batchv1.Job{
+				Spec:   batchv1.JobSpec{BackoffLimit: int32ptr(1)},
+				Status: batchv1.JobStatus{Failed: 2},
+			},
+			wantFailed: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			failed, _, err := checkJobTerminallyFailed(context.Background(), nil, tc.job)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if failed != tc.wantFailed {
+				t.Errorf("failed = %v, want %v", failed, tc.wantFailed)
+			}
+		})
+	}
+}
+
+func TestCheckDeploymentTerminallyFailed(t *testing.T) {
+	cases := []struct {
+		name       string
+		deployment *appsv1.Deployment
+		wantFailed bool
+	}{
+		{
+			name:       "still progressing",
+			deployment: &appsv1.Deployment{},
+			wantFailed: false,
+		},
+		{
+			name: "progress deadline exceeded",
+			deployment: &appsv1.Deployment{Status: appsv1.DeploymentStatus{
+				Conditions: []appsv1.DeploymentCondition{
+					{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"},
+				},
+			}},
+			wantFailed: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			failed, _, err := checkDeploymentTerminallyFailed(context.Background(), nil, tc.deployment)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if failed != tc.wantFailed {
+				t.Errorf("failed = %v, want %v", failed, tc.wantFailed)
+			}
+		})
+	}
+}
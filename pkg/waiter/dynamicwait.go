@@ -0,0 +1,90 @@
+package waiter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// ParseGVR parses a "group/version/resource" or "version/resource" string (the
+// latter for core-group resources, e.g. "v1/pods") into a GroupVersionResource,
+// mirroring the flag format kubectl uses for --raw style API paths.
+func ParseGVR(s string) (schema.GroupVersionResource, error) {
+	parts := strings.Split(s, "/")
+	switch len(parts) {
+	case 2:
+		return schema.GroupVersionResource{Group: "", Version: parts[0], Resource: parts[1]}, nil
+	case 3:
+		return schema.GroupVersionResource{Group: parts[0], Version: parts[1], Resource: parts[2]}, nil
+	default:
+		return schema.GroupVersionResource{}, fmt.Errorf("invalid --gvr %q: expected \"group/version/resource\" or \"version/resource\"", s)
+	}
+}
+
+// jsonPathMatches evaluates jsonPathExpr (a kubectl-style JSONPath template,
+// e.g. `{.status.conditions[?(@.type=="Ready")].status}`) against obj and
+// reports whether any result equals expected. This is the same predicate
+// style e2e-framework's conditions.ResourceMatch uses to wait on arbitrary
+// CRDs without needing a typed Checker for every kind.
+func jsonPathMatches(jsonPathExpr, expected string, obj *unstructured.Unstructured) (bool, error) {
+	jp := jsonpath.New("wait-for-k8s")
+	jp.AllowMissingKeys(true)
+	if err := jp.Parse(jsonPathExpr); err != nil {
+		return false, fmt.Errorf("invalid jsonpath expression %q: %v", jsonPathExpr, err)
+	}
+
+	results, err := jp.FindResults(obj.Object)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate jsonpath %q: %v", jsonPathExpr, err)
+	}
+	for _, set := range results {
+		for _, value := range set {
+			if fmt.Sprintf("%v", value.Interface()) == expected {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// waitForDynamicResourceReadiness waits until every unstructured object
+// matching gvr+namespace+labelSelector satisfies jsonPathExpr == expected. It
+// shares the list-watch-backoff skeleton with waitForResourceReadiness via
+// watchLoop, so a CRD wait behaves identically to a built-in one from the
+// caller's perspective; the only thing specific to this path is how an
+// object's readiness is judged.
+func waitForDynamicResourceReadiness(ctx context.Context, dynamicClient dynamic.Interface, reporter Reporter, gvr schema.GroupVersionResource, namespace, labelSelector, fieldSelector, jsonPathExpr, expected string) error {
+	resourceClient := dynamicClient.Resource(gvr).Namespace(namespace)
+
+	checkReady := func(ctx context.Context, obj runtime.Object) (bool, string, error) {
+		item, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return false, "", fmt.Errorf("expected *unstructured.Unstructured, got %T", obj)
+		}
+		matched, err := jsonPathMatches(jsonPathExpr, expected, item)
+		if err != nil {
+			return false, "", err
+		}
+		if matched {
+			return true, "", nil
+		}
+		return false, fmt.Sprintf("%s did not match %q", jsonPathExpr, expected), nil
+	}
+
+	return watchLoop(ctx, reporter, gvr.Resource, labelSelector,
+		func(ctx context.Context) (runtime.Object, error) {
+			return resourceClient.List(ctx, metav1.ListOptions{LabelSelector: labelSelector, FieldSelector: fieldSelector})
+		},
+		func(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+			return resourceClient.Watch(ctx, metav1.ListOptions{LabelSelector: labelSelector, FieldSelector: fieldSelector, ResourceVersion: resourceVersion})
+		},
+		checkReady, nil) // no general notion of terminal failure for an arbitrary CRD
+}
@@ -0,0 +1,65 @@
+package waiter
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestParseGVR(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    schema.GroupVersionResource
+		wantErr bool
+	}{
+		{"v1/pods", schema.GroupVersionResource{Version: "v1", Resource: "pods"}, false},
+		{"cert-manager.io/v1/certificates", schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}, false},
+		{"not-a-gvr", schema.GroupVersionResource{}, true},
+	}
+	for _, tc := range cases {
+		got, err := ParseGVR(tc.in)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("ParseGVR(%q): expected error, got nil", tc.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("ParseGVR(%q): unexpected error: %v", tc.in, err)
+		}
+		if got != tc.want {
+			t.Errorf("ParseGVR(%q) = %+v, want %+v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestJSONPathMatches(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			},
+		},
+	}}
+
+	matched, err := jsonPathMatches(`{.status.conditions[?(@.type=="Ready")].status}`, "True", obj)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !matched {
+		t.Errorf("expected match, got false")
+	}
+
+	matched, err = jsonPathMatches(`{.status.conditions[?(@.type=="Ready")].status}`, "False", obj)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if matched {
+		t.Errorf("expected no match, got true")
+	}
+
+	if _, err := jsonPathMatches(`{.status.[}`, "True", obj); err == nil {
+		t.Errorf("expected error for invalid jsonpath, got nil")
+	}
+}
@@ -0,0 +1,124 @@
+package waiter
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// EventType identifies the kind of readiness transition a Reporter is told
+// about. It mirrors the states Istio's progress.Log tracks per component:
+// discovery, per-object readiness (with a reason when not ready), and the
+// terminal all-ready/timeout outcomes.
+type EventType string
+
+const (
+	EventNoneFound      EventType = "none_found"
+	EventNotReady       EventType = "not_ready"
+	EventReady          EventType = "ready"
+	EventAllReady       EventType = "all_ready"
+	EventReconnecting   EventType = "reconnecting"
+	EventTerminalFailed EventType = "terminal_failed"
+)
+
+// Event describes a single readiness transition for one resource type, and
+// optionally one object of that type.
+type Event struct {
+	Type         EventType `json:"type"`
+	Timestamp    time.Time `json:"timestamp"`
+	ResourceType string    `json:"resourceType"`
+	Name         string    `json:"name,omitempty"`
+	Reason       string    `json:"reason,omitempty"`
+}
+
+// Reporter is how the wait engine surfaces progress to the user. Text is
+// the default, human-oriented mode; Events and JSON give scripts and CI
+// systems something they can parse instead of scraping log lines.
+type Reporter interface {
+	Emit(Event)
+	// Summary is called exactly once, after the wait concludes, with the
+	// final outcome. Reporters that only care about the end result (e.g.
+	// the "json" reporter) do their printing here instead of from Emit.
+	Summary(ready bool, err error)
+}
+
+// NewReporter builds the Reporter named by output, which must be "", "text",
+// "json", or "events".
+func NewReporter(output string) (Reporter, error) {
+	switch output {
+	case "", "text":
+		return textReporter{}, nil
+	case "json":
+		return &jsonReporter{}, nil
+	case "events":
+		return eventsReporter{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported --output %q: must be one of text, json, events", output)
+	}
+}
+
+// textReporter prints the tool's original human-readable stdout/log lines,
+// adding the not-ready reason when there is one, plus lines for watch
+// reconnects and terminal failures.
+type textReporter struct{}
+
+func (textReporter) Emit(e Event) {
+	switch e.Type {
+	case EventNoneFound:
+		log.Printf("No %ss found with label selector '%s', waiting...\n", e.ResourceType, e.Reason)
+	case EventNotReady:
+		if e.Reason != "" {
+			fmt.Printf("%s %s is not ready (%s), waiting...\n", e.ResourceType, e.Name, e.Reason)
+		} else {
+			fmt.Printf("%s %s is not ready, waiting...\n", e.ResourceType, e.Name)
+		}
+	case EventReady:
+		fmt.Printf("%s %s is ready.\n", e.ResourceType, e.Name)
+	case EventAllReady:
+		fmt.Printf("All %ss are ready!\n", e.ResourceType)
+	case EventReconnecting:
+		if e.Name != "" {
+			log.Printf("%s %s: %s\n", e.ResourceType, e.Name, e.Reason)
+		} else {
+			log.Printf("%s: %s\n", e.ResourceType, e.Reason)
+		}
+	case EventTerminalFailed:
+		fmt.Printf("%s %s has failed terminally (%s), aborting.\n", e.ResourceType, e.Name, e.Reason)
+	}
+}
+
+func (textReporter) Summary(ready bool, err error) {}
+
+// eventsReporter streams every transition as one JSON object per line, e.g.
+// for piping into a log aggregator or annotating a CI step in real time.
+type eventsReporter struct{}
+
+func (eventsReporter) Emit(e Event) {
+	e.Timestamp = time.Now()
+	if data, err := json.Marshal(e); err == nil {
+		fmt.Println(string(data))
+	}
+}
+
+func (eventsReporter) Summary(ready bool, err error) {}
+
+// jsonReporter suppresses per-event output and instead prints a single,
+// final JSON result once the wait concludes, for callers that only want exit
+// status plus a machine-readable reason rather than a progress stream.
+type jsonReporter struct{}
+
+func (r *jsonReporter) Emit(Event) {}
+
+func (r *jsonReporter) Summary(ready bool, err error) {
+	result := struct {
+		Ready bool   `json:"ready"`
+		Error string `json:"error,omitempty"`
+	}{Ready: ready}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	if data, marshalErr := json.Marshal(result); marshalErr == nil {
+		fmt.Println(string(data))
+	}
+}
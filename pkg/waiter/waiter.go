@@ -0,0 +1,232 @@
+package waiter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+// watchBackoff bounds the reconnect delay used when a resource watch drops or
+// errors out. It starts small so transient hiccups recover fast, and caps out
+// so a persistently broken API server doesn't stall readiness detection for
+// minutes between attempts.
+const (
+	watchBackoffMin = 500 * time.Millisecond
+	watchBackoffMax = 30 * time.Second
+)
+
+// nextBackoff doubles the previous delay, capped at watchBackoffMax.
+func nextBackoff(previous time.Duration) time.Duration {
+	if previous <= 0 {
+		return watchBackoffMin
+	}
+	next := previous * 2
+	if next > watchBackoffMax {
+		return watchBackoffMax
+	}
+	return next
+}
+
+// Waiter is the importable wait engine: everything the CLI does is a thin
+// wrapper around a Waiter built from the caller's own clients. Embedding
+// programs (operators, test harnesses, CI tooling) construct one with
+// NewWaiter and call WaitFor or one of the per-kind convenience methods
+// instead of shelling out to the binary.
+type Waiter struct {
+	Clients  *Clients
+	Dynamic  dynamic.Interface
+	Reporter Reporter
+}
+
+// NewWaiter builds a Waiter from the given clients and reporter. Dynamic may
+// be nil if the caller never waits on a Target with GVR set. Reporter may be
+// nil, in which case the human-readable text reporter is used.
+func NewWaiter(clients *Clients, dynamicClient dynamic.Interface, reporter Reporter) *Waiter {
+	if reporter == nil {
+		reporter = textReporter{}
+	}
+	return &Waiter{Clients: clients, Dynamic: dynamicClient, Reporter: reporter}
+}
+
+// Target describes one thing to wait on, either a built-in Kind ("pod",
+// "deployment", ...) or an arbitrary CRD via GVR+JSONPath. It's the same
+// shape WaitTarget uses in a --wait-spec manifest, minus the Name used there
+// purely for labeling concurrent targets.
+type Target struct {
+	Kind          string
+	GVR           string
+	Namespace     string
+	LabelSelector string
+	FieldSelector string
+	JSONPath      string
+	JSONPathValue string
+	// FailFast aborts the wait as soon as a matching object reaches a state
+	// it can't recover from on its own (e.g. a Job past its backoff limit)
+	// instead of blocking until --timeout. Only honored for built-in Kinds
+	// with a registered TerminalChecker; GVR targets have no general notion
+	// of "doomed" to check.
+	FailFast bool
+}
+
+// TerminalError is returned by WaitFor when FailFast is set and a matching
+// object reaches a terminal failure state. Callers can type-assert it to
+// distinguish "gave up because something is doomed" from a plain timeout.
+type TerminalError struct {
+	ResourceType string
+	Name         string
+	Reason       string
+}
+
+func (e *TerminalError) Error() string {
+	return fmt.Sprintf("%s %s failed terminally: %s", e.ResourceType, e.Name, e.Reason)
+}
+
+// WaitFor blocks until target is ready, dispatching to the built-in checker
+// registry when Kind is set or to the generic JSONPath predicate when GVR is
+// set.
+func (w *Waiter) WaitFor(ctx context.Context, target Target) error {
+	if target.GVR != "" {
+		resource, err := ParseGVR(target.GVR)
+		if err != nil {
+			return err
+		}
+		jsonPathValue := target.JSONPathValue
+		if jsonPathValue == "" {
+			jsonPathValue = "True"
+		}
+		return waitForDynamicResourceReadiness(ctx, w.Dynamic, w.Reporter, resource, target.Namespace, target.LabelSelector, target.FieldSelector, target.JSONPath, jsonPathValue)
+	}
+	return waitForResourceReadiness(ctx, w.Clients, w.Reporter, target.Namespace, target.LabelSelector, target.FieldSelector, target.Kind, target.FailFast)
+}
+
+// WaitForPods waits until every Pod matching namespace+labelSelector is
+// ready.
+func (w *Waiter) WaitForPods(ctx context.Context, namespace, labelSelector string) error {
+	return w.WaitFor(ctx, Target{Kind: "pod", Namespace: namespace, LabelSelector: labelSelector})
+}
+
+// WaitForDeployments waits until every Deployment matching
+// namespace+labelSelector is ready.
+func (w *Waiter) WaitForDeployments(ctx context.Context, namespace, labelSelector string) error {
+	return w.WaitFor(ctx, Target{Kind: "deployment", Namespace: namespace, LabelSelector: labelSelector})
+}
+
+// listResources performs the initial list for resourceType, used to seed the
+// watch with a starting resource version and the set of objects already known
+// to exist. CRDs are cluster-scoped, so namespace is ignored for that type.
+func listResources(ctx context.Context, clients *Clients, namespace, labelSelector, fieldSelector, resourceType string) (runtime.Object, error) {
+	opts := metav1.ListOptions{LabelSelector: labelSelector, FieldSelector: fieldSelector}
+
+	switch resourceType {
+	case "pod":
+		return clients.Kube.CoreV1().Pods(namespace).List(ctx, opts)
+	case "job":
+		return clients.Kube.BatchV1().Jobs(namespace).List(ctx, opts)
+	case "deployment":
+		return clients.Kube.AppsV1().Deployments(namespace).List(ctx, opts)
+	case "statefulset":
+		return clients.Kube.AppsV1().StatefulSets(namespace).List(ctx, opts)
+	case "daemonset":
+		return clients.Kube.AppsV1().DaemonSets(namespace).List(ctx, opts)
+	case "replicaset":
+		return clients.Kube.AppsV1().ReplicaSets(namespace).List(ctx, opts)
+	case "service":
+		return clients.Kube.CoreV1().Services(namespace).List(ctx, opts)
+	case "pvc":
+		return clients.Kube.CoreV1().PersistentVolumeClaims(namespace).List(ctx, opts)
+	case "namespace":
+		return clients.Kube.CoreV1().Namespaces().List(ctx, opts)
+	case "crd":
+		return clients.APIExt.ApiextensionsV1().CustomResourceDefinitions().List(ctx, opts)
+	case "cronjob":
+		return clients.Kube.BatchV1().CronJobs(namespace).List(ctx, opts)
+	default:
+		return nil, fmt.Errorf("unsupported resource type: %s", resourceType)
+	}
+}
+
+// watchResources opens a watch for resourceType starting at resourceVersion.
+func watchResources(ctx context.Context, clients *Clients, namespace, labelSelector, fieldSelector, resourceType, resourceVersion string) (watch.Interface, error) {
+	opts := metav1.ListOptions{
+		LabelSelector:   labelSelector,
+		FieldSelector:   fieldSelector,
+		ResourceVersion: resourceVersion,
+		Watch:           true,
+	}
+
+	switch resourceType {
+	case "pod":
+		return clients.Kube.CoreV1().Pods(namespace).Watch(ctx, opts)
+	case "job":
+		return clients.Kube.BatchV1().Jobs(namespace).Watch(ctx, opts)
+	case "deployment":
+		return clients.Kube.AppsV1().Deployments(namespace).Watch(ctx, opts)
+	case "statefulset":
+		return clients.Kube.AppsV1().StatefulSets(namespace).Watch(ctx, opts)
+	case "daemonset":
+		return clients.Kube.AppsV1().DaemonSets(namespace).Watch(ctx, opts)
+	case "replicaset":
+		return clients.Kube.AppsV1().ReplicaSets(namespace).Watch(ctx, opts)
+	case "service":
+		return clients.Kube.CoreV1().Services(namespace).Watch(ctx, opts)
+	case "pvc":
+		return clients.Kube.CoreV1().PersistentVolumeClaims(namespace).Watch(ctx, opts)
+	case "namespace":
+		return clients.Kube.CoreV1().Namespaces().Watch(ctx, opts)
+	case "crd":
+		return clients.APIExt.ApiextensionsV1().CustomResourceDefinitions().Watch(ctx, opts)
+	case "cronjob":
+		return clients.Kube.BatchV1().CronJobs(namespace).Watch(ctx, opts)
+	default:
+		return nil, fmt.Errorf("unsupported resource type: %s", resourceType)
+	}
+}
+
+// waitForResourceReadiness subscribes once to namespace+labelSelector for
+// resourceType and blocks until every object it has seen is ready, reporting
+// readiness transitions as they happen instead of re-listing on an interval.
+// Watch failures are retried with a bounded exponential backoff rather than
+// aborting the wait outright. The list/watch/backoff mechanics themselves
+// live in watchLoop, shared with waitForDynamicResourceReadiness; this just
+// supplies how to talk to the typed clientset and how to judge readiness.
+func waitForResourceReadiness(ctx context.Context, clients *Clients, reporter Reporter, namespace, labelSelector, fieldSelector, resourceType string, failFast bool) error {
+	checkReady := func(ctx context.Context, obj runtime.Object) (bool, string, error) {
+		return isResourceReady(ctx, clients, resourceType, obj)
+	}
+	var checkTerminal terminalFailureFunc
+	if failFast {
+		checkTerminal = func(ctx context.Context, name string, obj runtime.Object) error {
+			return checkTerminalFailure(ctx, clients, reporter, resourceType, name, obj)
+		}
+	}
+	return watchLoop(ctx, reporter, resourceType, labelSelector,
+		func(ctx context.Context) (runtime.Object, error) {
+			return listResources(ctx, clients, namespace, labelSelector, fieldSelector, resourceType)
+		},
+		func(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+			return watchResources(ctx, clients, namespace, labelSelector, fieldSelector, resourceType, resourceVersion)
+		},
+		checkReady, checkTerminal)
+}
+
+// checkTerminalFailure consults the TerminalChecker registered for
+// resourceType (if any) and, if obj has reached a state it can't recover
+// from on its own, emits an EventTerminalFailed and returns a *TerminalError
+// so the caller can abort the wait immediately instead of blocking until
+// --timeout.
+func checkTerminalFailure(ctx context.Context, clients *Clients, reporter Reporter, resourceType, name string, obj runtime.Object) error {
+	failed, reason, err := isResourceTerminallyFailed(ctx, clients, resourceType, obj)
+	if err != nil {
+		return fmt.Errorf("error checking terminal state for %s %s: %v", resourceType, name, err)
+	}
+	if !failed {
+		return nil
+	}
+	reporter.Emit(Event{Type: EventTerminalFailed, ResourceType: resourceType, Name: name, Reason: reason})
+	return &TerminalError{ResourceType: resourceType, Name: name, Reason: reason}
+}
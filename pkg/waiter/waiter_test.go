@@ -0,0 +1,27 @@
+package waiter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextBackoff(t *testing.T) {
+	cases := []struct {
+		name     string
+		previous time.Duration
+		want     time.Duration
+	}{
+		{"zero starts at the floor", 0, watchBackoffMin},
+		{"negative starts at the floor", -time.Second, watchBackoffMin},
+		{"doubles", 2 * time.Second, 4 * time.Second},
+		{"caps at the ceiling", watchBackoffMax, watchBackoffMax},
+		{"doubling past the ceiling clamps", watchBackoffMax/2 + time.Second, watchBackoffMax},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := nextBackoff(tc.previous); got != tc.want {
+				t.Errorf("nextBackoff(%s) = %s, want %s", tc.previous, got, tc.want)
+			}
+		})
+	}
+}
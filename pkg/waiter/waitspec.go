@@ -0,0 +1,92 @@
+package waiter
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"golang.org/x/sync/errgroup"
+	"sigs.k8s.io/yaml"
+)
+
+// WaitSpec describes a set of heterogeneous resources that must all become
+// ready together, e.g. the Deployments, Services and a cert-manager
+// Certificate that make up one application stack. It's read from the file
+// passed via --wait-spec.
+type WaitSpec struct {
+	Targets []WaitTarget `json:"targets"`
+}
+
+// WaitTarget is a single entry in a WaitSpec. Kind selects a built-in
+// resource type ("pod", "deployment", ...); set GVR instead to wait on an
+// arbitrary CRD via the same JSONPath predicate --gvr mode uses.
+type WaitTarget struct {
+	Name          string `json:"name"`
+	Kind          string `json:"kind,omitempty"`
+	GVR           string `json:"gvr,omitempty"`
+	Namespace     string `json:"namespace"`
+	LabelSelector string `json:"labelSelector,omitempty"`
+	FieldSelector string `json:"fieldSelector,omitempty"`
+	JSONPath      string `json:"jsonPath,omitempty"`
+	JSONPathValue string `json:"jsonPathValue,omitempty"`
+	FailFast      bool   `json:"failFast,omitempty"`
+}
+
+// LoadWaitSpec reads and parses a --wait-spec manifest.
+func LoadWaitSpec(path string) (*WaitSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wait spec %s: %v", path, err)
+	}
+	var spec WaitSpec
+	if err := yaml.UnmarshalStrict(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse wait spec %s: %v", path, err)
+	}
+	if len(spec.Targets) == 0 {
+		return nil, fmt.Errorf("wait spec %s declares no targets", path)
+	}
+	for i, target := range spec.Targets {
+		if target.Kind == "" && target.GVR == "" {
+			return nil, fmt.Errorf("target %d (%s) must set either kind or gvr", i, target.Name)
+		}
+		if target.GVR != "" && target.JSONPath == "" {
+			return nil, fmt.Errorf("target %d (%s) sets gvr but no jsonPath", i, target.Name)
+		}
+		if target.GVR != "" && target.FailFast {
+			return nil, fmt.Errorf("target %d (%s) sets failFast but gvr: there's no general notion of a terminal failure for an arbitrary CRD", i, target.Name)
+		}
+	}
+	return &spec, nil
+}
+
+// RunWaitSpec waits on every target in spec concurrently and only returns
+// once all of them are ready (or one fails/times out). Each target runs
+// through w.WaitFor, the same watch-and-backoff loop as the single-resource
+// CLI mode, just fanned out under an errgroup so a slow target doesn't block
+// the others from reporting progress.
+func RunWaitSpec(ctx context.Context, w *Waiter, spec *WaitSpec) error {
+	group, groupCtx := errgroup.WithContext(ctx)
+
+	for _, target := range spec.Targets {
+		target := target
+		group.Go(func() error {
+			err := w.WaitFor(groupCtx, Target{
+				Kind:          target.Kind,
+				GVR:           target.GVR,
+				Namespace:     target.Namespace,
+				LabelSelector: target.LabelSelector,
+				FieldSelector: target.FieldSelector,
+				JSONPath:      target.JSONPath,
+				JSONPathValue: target.JSONPathValue,
+				FailFast:      target.FailFast,
+			})
+			if err != nil {
+				return fmt.Errorf("[%s] %v", target.Name, err)
+			}
+			w.Reporter.Emit(Event{Type: EventAllReady, ResourceType: target.Name})
+			return nil
+		})
+	}
+
+	return group.Wait()
+}
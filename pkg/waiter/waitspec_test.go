@@ -0,0 +1,71 @@
+package waiter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeWaitSpec(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "wait-spec.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("failed to write wait spec fixture: %v", err)
+	}
+	return path
+}
+
+func TestLoadWaitSpec(t *testing.T) {
+	t.Run("valid spec", func(t *testing.T) {
+		path := writeWaitSpec(t, `
+targets:
+  - name: app
+    kind: deployment
+    namespace: default
+  - name: cert
+    gvr: cert-manager.io/v1/certificates
+    jsonPath: '{.status.conditions[?(@.type=="Ready")].status}'
+`)
+		spec, err := LoadWaitSpec(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(spec.Targets) != 2 {
+			t.Fatalf("expected 2 targets, got %d", len(spec.Targets))
+		}
+	})
+
+	t.Run("no targets", func(t *testing.T) {
+		path := writeWaitSpec(t, "targets: []\n")
+		if _, err := LoadWaitSpec(path); err == nil {
+			t.Errorf("expected error for empty targets, got nil")
+		}
+	})
+
+	t.Run("missing kind and gvr", func(t *testing.T) {
+		path := writeWaitSpec(t, "targets:\n  - name: app\n    namespace: default\n")
+		if _, err := LoadWaitSpec(path); err == nil {
+			t.Errorf("expected error for target with neither kind nor gvr, got nil")
+		}
+	})
+
+	t.Run("gvr without jsonPath", func(t *testing.T) {
+		path := writeWaitSpec(t, "targets:\n  - name: cert\n    gvr: cert-manager.io/v1/certificates\n")
+		if _, err := LoadWaitSpec(path); err == nil {
+			t.Errorf("expected error for gvr target with no jsonPath, got nil")
+		}
+	})
+
+	t.Run("gvr with failFast", func(t *testing.T) {
+		path := writeWaitSpec(t, `
+targets:
+  - name: cert
+    gvr: cert-manager.io/v1/certificates
+    jsonPath: '{.status.conditions[?(@.type=="Ready")].status}'
+    failFast: true
+`)
+		if _, err := LoadWaitSpec(path); err == nil {
+			t.Errorf("expected error for gvr target with failFast, got nil")
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package waiter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// readinessFunc reports whether obj is ready, with a human-readable reason
+// when it isn't. It's the one piece that differs between a built-in Kind
+// (dispatched through the Checker registry) and a GVR target (a JSONPath
+// predicate) — everything else about waiting on one versus the other is
+// identical.
+type readinessFunc func(ctx context.Context, obj runtime.Object) (ready bool, reason string, err error)
+
+// terminalFailureFunc reports a non-nil error (normally a *TerminalError) if
+// obj has reached a state it can't recover from on its own. nil means the
+// caller never opted into --fail-fast, or there's no general notion of
+// "doomed" for this target (e.g. an arbitrary CRD).
+type terminalFailureFunc func(ctx context.Context, name string, obj runtime.Object) error
+
+// watchLoop is the list-watch-backoff skeleton shared by built-in and
+// dynamic/CRD readiness waits: list once to seed known state and a resource
+// version, watch from there, and relist with exponential backoff whenever
+// the watch fails to start or drops. list/watchFn are how the caller talks
+// to the right API (typed clientset vs. dynamic client); checkReady is how
+// it judges one object.
+func watchLoop(
+	ctx context.Context,
+	reporter Reporter,
+	resourceType, labelSelector string,
+	list func(ctx context.Context) (runtime.Object, error),
+	watchFn func(ctx context.Context, resourceVersion string) (watch.Interface, error),
+	checkReady readinessFunc,
+	checkTerminal terminalFailureFunc,
+) error {
+	accessor := meta.NewAccessor()
+	known := map[string]bool{} // object name -> ready
+
+	reportReady := func() bool {
+		if len(known) == 0 {
+			return false
+		}
+		for _, ready := range known {
+			if !ready {
+				return false
+			}
+		}
+		reporter.Emit(Event{Type: EventAllReady, ResourceType: resourceType})
+		return true
+	}
+
+	backoff := time.Duration(0)
+	for {
+		listObject, err := list(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list %ss: %v", resourceType, err)
+		}
+
+		listAccessor, err := meta.ListAccessor(listObject)
+		if err != nil {
+			return fmt.Errorf("failed to read list metadata for %ss: %v", resourceType, err)
+		}
+
+		items, err := meta.ExtractList(listObject)
+		if err != nil {
+			return fmt.Errorf("failed to extract list of %ss: %v", resourceType, err)
+		}
+		// Rebuild known from this list rather than mutating the old map in
+		// place: an object that disappeared while the watch was down (e.g.
+		// GC'd during a reconnect) never gets a Deleted event to remove its
+		// stale "not ready" entry, which would otherwise wedge reportReady
+		// forever even once everything still alive is ready.
+		known = map[string]bool{}
+		for _, item := range items {
+			name, _ := accessor.Name(item)
+			ready, reason, err := checkReady(ctx, item)
+			if err != nil {
+				return fmt.Errorf("error checking readiness for %s %s: %v", resourceType, name, err)
+			}
+			known[name] = ready
+			if ready {
+				reporter.Emit(Event{Type: EventReady, ResourceType: resourceType, Name: name})
+				continue
+			}
+			reporter.Emit(Event{Type: EventNotReady, ResourceType: resourceType, Name: name, Reason: reason})
+			if checkTerminal != nil {
+				if termErr := checkTerminal(ctx, name, item); termErr != nil {
+					return termErr
+				}
+			}
+		}
+		if len(items) == 0 {
+			reporter.Emit(Event{Type: EventNoneFound, ResourceType: resourceType, Reason: labelSelector})
+		} else if reportReady() {
+			return nil
+		}
+
+		watcher, err := watchFn(ctx, listAccessor.GetResourceVersion())
+		if err != nil {
+			backoff = nextBackoff(backoff)
+			reporter.Emit(Event{Type: EventReconnecting, ResourceType: resourceType, Reason: fmt.Sprintf("failed to start watch: %v, retrying in %s", err, backoff)})
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("timed out waiting for %ss to become ready: %v", resourceType, ctx.Err())
+			case <-time.After(backoff):
+				continue
+			}
+		}
+
+		ready, err := consumeWatchEvents(ctx, reporter, watcher, resourceType, accessor, known, reportReady, checkReady, checkTerminal)
+		watcher.Stop()
+		if err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("timed out waiting for %ss to become ready: %v", resourceType, ctx.Err())
+			}
+			backoff = nextBackoff(backoff)
+			reporter.Emit(Event{Type: EventReconnecting, ResourceType: resourceType, Reason: fmt.Sprintf("watch dropped: %v, reconnecting in %s", err, backoff)})
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("timed out waiting for %ss to become ready: %v", resourceType, ctx.Err())
+			case <-time.After(backoff):
+				continue
+			}
+		}
+		if ready {
+			return nil
+		}
+		// Watch channel closed cleanly (e.g. resource version too old); relist and resubscribe.
+		backoff = 0
+	}
+}
+
+// consumeWatchEvents drains watcher until all known objects are ready, the
+// context is cancelled, or the channel closes. It mutates known in place so
+// reportReady, which reads the same map, sees every update; on reconnect the
+// caller rebuilds known from a fresh list.
+func consumeWatchEvents(ctx context.Context, reporter Reporter, watcher watch.Interface, resourceType string, accessor meta.MetadataAccessor, known map[string]bool, reportReady func() bool, checkReady readinessFunc, checkTerminal terminalFailureFunc) (bool, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return false, nil
+			}
+			switch event.Type {
+			case watch.Error:
+				if status, ok := event.Object.(*metav1.Status); ok {
+					return false, apierrors.FromObject(status)
+				}
+				return false, fmt.Errorf("watch error: %v", event.Object)
+			case watch.Deleted:
+				name, _ := accessor.Name(event.Object)
+				delete(known, name)
+			case watch.Added, watch.Modified:
+				name, _ := accessor.Name(event.Object)
+				ready, reason, err := checkReady(ctx, event.Object)
+				if err != nil {
+					return false, fmt.Errorf("error checking readiness for %s %s: %v", resourceType, name, err)
+				}
+				known[name] = ready
+				if ready {
+					reporter.Emit(Event{Type: EventReady, ResourceType: resourceType, Name: name})
+				} else {
+					reporter.Emit(Event{Type: EventNotReady, ResourceType: resourceType, Name: name, Reason: reason})
+					if checkTerminal != nil {
+						if termErr := checkTerminal(ctx, name, event.Object); termErr != nil {
+							return false, termErr
+						}
+					}
+				}
+			}
+			if reportReady() {
+				return true, nil
+			}
+		}
+	}
+}